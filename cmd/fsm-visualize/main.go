@@ -0,0 +1,52 @@
+// Command fsm-visualize prints the static state graph of a registered
+// FSM as Graphviz DOT or a Mermaid flowchart.
+//
+// This repo ships no example machine to load out of the box, so wire up
+// your own service.FsmService in loadFsm below (swap in whatever
+// journeystore.JourneyStore and states your application registers with
+// service.NewFsmService) and build this command from your own module.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thevibegod/fsm/service"
+	"github.com/thevibegod/fsm/visualize"
+)
+
+func main() {
+	format := flag.String("format", "dot", "output format: dot or mermaid")
+	flag.Parse()
+
+	fs, err := loadFsm()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fsm-visualize:", err.Error())
+		os.Exit(1)
+	}
+
+	var diagram string
+	var renderErr error
+	switch *format {
+	case "dot":
+		diagram, renderErr = visualize.ToGraphviz[any](fs)
+	case "mermaid":
+		diagram, renderErr = visualize.ToMermaid[any](fs)
+	default:
+		fmt.Fprintf(os.Stderr, "fsm-visualize: unknown format %q (want dot or mermaid)\n", *format)
+		os.Exit(1)
+	}
+	if renderErr != nil {
+		fmt.Fprintln(os.Stderr, "fsm-visualize:", renderErr.Error())
+		os.Exit(1)
+	}
+
+	fmt.Print(diagram)
+}
+
+// loadFsm builds the FsmService to visualize. Replace this with whatever
+// your application passes to service.NewFsmService.
+func loadFsm() (service.FsmService[any], error) {
+	return nil, fmt.Errorf("loadFsm is not wired up: register your own service.NewFsmService call here")
+}
@@ -0,0 +1,8 @@
+package constants
+
+const (
+	EventNameStart              = "start"
+	EventNameResume             = "resume"
+	EventNameBack               = "back"
+	EventNameTransitionComplete = "transition_complete"
+)
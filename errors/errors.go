@@ -0,0 +1,34 @@
+package errors
+
+type FsmError struct {
+	Code    string
+	Message string
+}
+
+func (e *FsmError) Error() string {
+	return e.Message
+}
+
+func InternalSystemError(message string) *FsmError {
+	return &FsmError{Code: "INTERNAL_SYSTEM_ERROR", Message: message}
+}
+
+func ByPassError(message string) *FsmError {
+	return &FsmError{Code: "BYPASS_ERROR", Message: message}
+}
+
+// NotFoundError reports that a lookup (e.g. JourneyStore.Get) found no
+// record for the requested key, as distinct from an error encountered
+// while trying to look it up. Callers that need to tell "doesn't exist"
+// apart from "couldn't check" should compare Code against NotFoundCode
+// rather than treating any non-nil error the same way.
+const NotFoundCode = "NOT_FOUND"
+
+func NotFoundError(message string) *FsmError {
+	return &FsmError{Code: NotFoundCode, Message: message}
+}
+
+// IsNotFound reports whether err is a NotFoundError.
+func IsNotFound(err *FsmError) bool {
+	return err != nil && err.Code == NotFoundCode
+}
@@ -0,0 +1,17 @@
+package journeystore
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var jidCounter uint64
+
+// newJID generates a JID unique within a process: a timestamp plus a
+// monotonic counter, so two journeys created in the same nanosecond
+// still sort and compare distinctly.
+func newJID() string {
+	n := atomic.AddUint64(&jidCounter, 1)
+	return fmt.Sprintf("jid-%d-%d", time.Now().UnixNano(), n)
+}
@@ -0,0 +1,41 @@
+package journeystore
+
+import (
+	"context"
+	"time"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	"github.com/thevibegod/fsm/model"
+)
+
+type JourneyStore[T any] interface {
+	Create(ctx context.Context) (model.Journey[T], *fsmErrors.FsmError)
+	Get(ctx context.Context, jid string) (model.Journey[T], *fsmErrors.FsmError)
+	Save(ctx context.Context, journey model.Journey[T]) *fsmErrors.FsmError
+	Delete(ctx context.Context, jid string) *fsmErrors.FsmError
+}
+
+// JourneyEvent is one immutable step in a journey's history. Seq and
+// Timestamp are assigned by the JourneyHistoryStore implementation when
+// the event is appended; callers populate every other field.
+type JourneyEvent[T any] struct {
+	JID             string
+	Seq             int
+	Timestamp       time.Time
+	FromState       string
+	ToState         string
+	Event           string
+	InputData       any
+	ResultData      any
+	JourneyDataDiff T
+}
+
+// JourneyHistoryStore is an optional extension of JourneyStore. Stores
+// that implement it let FsmService answer History, RewindTo, and Fork;
+// service.NewFsmService accepts a plain JourneyStore, so implementing
+// this interface is opt-in, not required.
+type JourneyHistoryStore[T any] interface {
+	JourneyStore[T]
+	AppendEvent(ctx context.Context, event JourneyEvent[T]) *fsmErrors.FsmError
+	Events(ctx context.Context, jid string) ([]JourneyEvent[T], *fsmErrors.FsmError)
+}
@@ -0,0 +1,74 @@
+package journeystore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	"github.com/thevibegod/fsm/model"
+)
+
+// MemoryStore is an in-process JourneyStore and JourneyHistoryStore. It
+// keeps every journey and its full event history in memory, so it is
+// useful for tests and short-lived processes but loses all state on
+// restart.
+type MemoryStore[T any] struct {
+	mu       sync.RWMutex
+	journeys map[string]model.Journey[T]
+	events   map[string][]JourneyEvent[T]
+}
+
+func NewMemoryStore[T any]() *MemoryStore[T] {
+	return &MemoryStore[T]{
+		journeys: make(map[string]model.Journey[T]),
+		events:   make(map[string][]JourneyEvent[T]),
+	}
+}
+
+func (s *MemoryStore[T]) Create(ctx context.Context) (model.Journey[T], *fsmErrors.FsmError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	journey := model.Journey[T]{JID: newJID()}
+	s.journeys[journey.JID] = journey
+	return journey, nil
+}
+
+func (s *MemoryStore[T]) Get(ctx context.Context, jid string) (model.Journey[T], *fsmErrors.FsmError) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	journey, ok := s.journeys[jid]
+	if !ok {
+		return model.Journey[T]{}, fsmErrors.NotFoundError("journey not found: " + jid)
+	}
+	return journey, nil
+}
+
+func (s *MemoryStore[T]) Save(ctx context.Context, journey model.Journey[T]) *fsmErrors.FsmError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.journeys[journey.JID] = journey
+	return nil
+}
+
+func (s *MemoryStore[T]) Delete(ctx context.Context, jid string) *fsmErrors.FsmError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.journeys, jid)
+	return nil
+}
+
+func (s *MemoryStore[T]) AppendEvent(ctx context.Context, event JourneyEvent[T]) *fsmErrors.FsmError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event.Seq = len(s.events[event.JID]) + 1
+	event.Timestamp = time.Now()
+	s.events[event.JID] = append(s.events[event.JID], event)
+	return nil
+}
+
+func (s *MemoryStore[T]) Events(ctx context.Context, jid string) ([]JourneyEvent[T], *fsmErrors.FsmError) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]JourneyEvent[T]{}, s.events[jid]...), nil
+}
@@ -0,0 +1,174 @@
+package journeystore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	"github.com/thevibegod/fsm/model"
+)
+
+// SQLStore is a JourneyStore and JourneyHistoryStore backed by a
+// two-table schema:
+//
+//	CREATE TABLE journeys (
+//		jid                   TEXT PRIMARY KEY,
+//		current_stage         TEXT NOT NULL,
+//		last_checkpoint_stage TEXT NOT NULL,
+//		data                  BLOB NOT NULL
+//	);
+//
+//	CREATE TABLE journey_events (
+//		jid               TEXT NOT NULL,
+//		seq               INTEGER NOT NULL,
+//		timestamp         TIMESTAMP NOT NULL,
+//		from_state        TEXT NOT NULL,
+//		to_state          TEXT NOT NULL,
+//		event             TEXT NOT NULL,
+//		input_data        BLOB,
+//		result_data       BLOB,
+//		journey_data_diff BLOB NOT NULL,
+//		PRIMARY KEY (jid, seq)
+//	);
+//
+// Journey data and event payloads are stored as JSON, so T (and
+// whatever InputData/ResultData hold) must be JSON-marshalable. The SQL
+// here targets the lowest common denominator of database/sql drivers;
+// swap in driver-specific upserts if you need better write throughput.
+//
+// The schema has no column for Journey.PendingBranches: Get always
+// returns it empty, so a fan-out interrupted mid-way is not resumed from
+// this store. Save only updates rows that Create inserted; saving a
+// journey whose JID was never created (such as a fan-out branch's child
+// journey) changes nothing and returns no error.
+type SQLStore[T any] struct {
+	db *sql.DB
+}
+
+func NewSQLStore[T any](db *sql.DB) *SQLStore[T] {
+	return &SQLStore[T]{db: db}
+}
+
+func (s *SQLStore[T]) Create(ctx context.Context) (model.Journey[T], *fsmErrors.FsmError) {
+	journey := model.Journey[T]{JID: newJID()}
+	data, jsonErr := json.Marshal(journey.Data)
+	if jsonErr != nil {
+		return model.Journey[T]{}, fsmErrors.InternalSystemError("failed to encode journey data: " + jsonErr.Error())
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO journeys (jid, current_stage, last_checkpoint_stage, data) VALUES (?, ?, ?, ?)`, journey.JID, "", "", data); err != nil {
+		return model.Journey[T]{}, fsmErrors.InternalSystemError("failed to create journey: " + err.Error())
+	}
+	return journey, nil
+}
+
+func (s *SQLStore[T]) Get(ctx context.Context, jid string) (model.Journey[T], *fsmErrors.FsmError) {
+	row := s.db.QueryRowContext(ctx, `SELECT current_stage, last_checkpoint_stage, data FROM journeys WHERE jid = ?`, jid)
+
+	var journey model.Journey[T]
+	var data []byte
+	if err := row.Scan(&journey.CurrentStage, &journey.LastCheckpointStage, &data); err != nil {
+		if err == sql.ErrNoRows {
+			return model.Journey[T]{}, fsmErrors.NotFoundError("journey not found: " + jid)
+		}
+		return model.Journey[T]{}, fsmErrors.InternalSystemError("failed to load journey " + jid + ": " + err.Error())
+	}
+	journey.JID = jid
+	if err := json.Unmarshal(data, &journey.Data); err != nil {
+		return model.Journey[T]{}, fsmErrors.InternalSystemError("failed to decode journey data: " + err.Error())
+	}
+	return journey, nil
+}
+
+func (s *SQLStore[T]) Save(ctx context.Context, journey model.Journey[T]) *fsmErrors.FsmError {
+	data, jsonErr := json.Marshal(journey.Data)
+	if jsonErr != nil {
+		return fsmErrors.InternalSystemError("failed to encode journey data: " + jsonErr.Error())
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE journeys SET current_stage = ?, last_checkpoint_stage = ?, data = ? WHERE jid = ?`,
+		journey.CurrentStage, journey.LastCheckpointStage, data, journey.JID); err != nil {
+		return fsmErrors.InternalSystemError("failed to save journey " + journey.JID + ": " + err.Error())
+	}
+	return nil
+}
+
+func (s *SQLStore[T]) Delete(ctx context.Context, jid string) *fsmErrors.FsmError {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM journeys WHERE jid = ?`, jid); err != nil {
+		return fsmErrors.InternalSystemError("failed to delete journey " + jid + ": " + err.Error())
+	}
+	return nil
+}
+
+func (s *SQLStore[T]) AppendEvent(ctx context.Context, event JourneyEvent[T]) *fsmErrors.FsmError {
+	inputData, err := json.Marshal(event.InputData)
+	if err != nil {
+		return fsmErrors.InternalSystemError("failed to encode input data: " + err.Error())
+	}
+	resultData, err := json.Marshal(event.ResultData)
+	if err != nil {
+		return fsmErrors.InternalSystemError("failed to encode result data: " + err.Error())
+	}
+	diffData, err := json.Marshal(event.JourneyDataDiff)
+	if err != nil {
+		return fsmErrors.InternalSystemError("failed to encode journey data diff: " + err.Error())
+	}
+
+	// Allocating seq and inserting on it must be atomic: two concurrent
+	// AppendEvent calls for the same jid (e.g. from a slow observer
+	// retrying an Execute, or a resumed fan-out branch) would otherwise
+	// race on the read-then-write and either collide on the (jid, seq)
+	// primary key or silently skip a seq.
+	tx, txErr := s.db.BeginTx(ctx, nil)
+	if txErr != nil {
+		return fsmErrors.InternalSystemError("failed to begin transaction for " + event.JID + ": " + txErr.Error())
+	}
+	defer tx.Rollback()
+
+	var seq int
+	row := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(seq), 0) + 1 FROM journey_events WHERE jid = ?`, event.JID)
+	if scanErr := row.Scan(&seq); scanErr != nil {
+		return fsmErrors.InternalSystemError("failed to allocate seq for " + event.JID + ": " + scanErr.Error())
+	}
+
+	if _, execErr := tx.ExecContext(ctx,
+		`INSERT INTO journey_events (jid, seq, timestamp, from_state, to_state, event, input_data, result_data, journey_data_diff) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.JID, seq, time.Now(), event.FromState, event.ToState, event.Event, inputData, resultData, diffData); execErr != nil {
+		return fsmErrors.InternalSystemError("failed to append journey event: " + execErr.Error())
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		return fsmErrors.InternalSystemError("failed to commit journey event for " + event.JID + ": " + commitErr.Error())
+	}
+	return nil
+}
+
+func (s *SQLStore[T]) Events(ctx context.Context, jid string) ([]JourneyEvent[T], *fsmErrors.FsmError) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT seq, timestamp, from_state, to_state, event, input_data, result_data, journey_data_diff FROM journey_events WHERE jid = ? ORDER BY seq ASC`, jid)
+	if err != nil {
+		return nil, fsmErrors.InternalSystemError("failed to load events for " + jid + ": " + err.Error())
+	}
+	defer rows.Close()
+
+	var events []JourneyEvent[T]
+	for rows.Next() {
+		var event JourneyEvent[T]
+		var inputData, resultData, diffData []byte
+		if scanErr := rows.Scan(&event.Seq, &event.Timestamp, &event.FromState, &event.ToState, &event.Event, &inputData, &resultData, &diffData); scanErr != nil {
+			return nil, fsmErrors.InternalSystemError("failed to scan journey event: " + scanErr.Error())
+		}
+		event.JID = jid
+		if unmarshalErr := json.Unmarshal(inputData, &event.InputData); unmarshalErr != nil {
+			return nil, fsmErrors.InternalSystemError("failed to decode input data: " + unmarshalErr.Error())
+		}
+		if unmarshalErr := json.Unmarshal(resultData, &event.ResultData); unmarshalErr != nil {
+			return nil, fsmErrors.InternalSystemError("failed to decode result data: " + unmarshalErr.Error())
+		}
+		if unmarshalErr := json.Unmarshal(diffData, &event.JourneyDataDiff); unmarshalErr != nil {
+			return nil, fsmErrors.InternalSystemError("failed to decode journey data diff: " + unmarshalErr.Error())
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
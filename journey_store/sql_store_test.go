@@ -0,0 +1,198 @@
+package journeystore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+)
+
+// fakeEventStore is a bare-bones backing store for fakeDriver: enough to
+// exercise AppendEvent's seq-allocation query and insert, nothing more.
+// mu is held for the lifetime of a transaction, the same way a
+// single-writer database (e.g. SQLite) serializes writers - that's what
+// lets this test prove AppendEvent's transaction actually closes the
+// race instead of just adding overhead around it.
+type fakeEventStore struct {
+	mu   sync.Mutex
+	seqs map[string]map[int]bool
+}
+
+var (
+	fakeStoresMu sync.Mutex
+	fakeStores   = map[string]*fakeEventStore{}
+	fakeDSNSeq   int64
+)
+
+func newFakeSeqDB(t *testing.T) (*sql.DB, *fakeEventStore) {
+	t.Helper()
+	dsn := fmt.Sprintf("fakeseq-%d", atomic.AddInt64(&fakeDSNSeq, 1))
+	store := &fakeEventStore{seqs: make(map[string]map[int]bool)}
+	fakeStoresMu.Lock()
+	fakeStores[dsn] = store
+	fakeStoresMu.Unlock()
+
+	db, err := sql.Open("fakeseq", dsn)
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+	return db, store
+}
+
+type fakeDriver struct{}
+
+func init() {
+	sql.Register("fakeseq", fakeDriver{})
+}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeStoresMu.Lock()
+	store := fakeStores[dsn]
+	fakeStoresMu.Unlock()
+	if store == nil {
+		return nil, fmt.Errorf("unknown fake store %s", dsn)
+	}
+	return &fakeConn{store: store}, nil
+}
+
+type fakeConn struct {
+	store *fakeEventStore
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{store: c.store, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.store.mu.Lock()
+	return &fakeTx{store: c.store}, nil
+}
+
+type fakeTx struct {
+	store *fakeEventStore
+}
+
+func (t *fakeTx) Commit() error   { t.store.mu.Unlock(); return nil }
+func (t *fakeTx) Rollback() error { t.store.mu.Unlock(); return nil }
+
+type fakeStmt struct {
+	store *fakeEventStore
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if !strings.Contains(s.query, "INSERT INTO journey_events") {
+		return nil, fmt.Errorf("fakeStmt: unsupported exec query %q", s.query)
+	}
+	jid, _ := args[0].(string)
+	seq := toInt(args[1])
+	if s.store.seqs[jid] == nil {
+		s.store.seqs[jid] = make(map[int]bool)
+	}
+	if s.store.seqs[jid][seq] {
+		return nil, fmt.Errorf("UNIQUE constraint failed: journey_events.jid, journey_events.seq")
+	}
+	s.store.seqs[jid][seq] = true
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.Contains(s.query, "SELECT COALESCE(MAX(seq)") {
+		return nil, fmt.Errorf("fakeStmt: unsupported query %q", s.query)
+	}
+	jid, _ := args[0].(string)
+	max := 0
+	for seq := range s.store.seqs[jid] {
+		if seq > max {
+			max = seq
+		}
+	}
+	return &singleIntRow{value: max + 1}, nil
+}
+
+func toInt(v driver.Value) int {
+	switch n := v.(type) {
+	case int64:
+		return int(n)
+	case int:
+		return n
+	default:
+		return 0
+	}
+}
+
+// singleIntRow is a driver.Rows with a single "seq" column and row,
+// enough to back the COALESCE(MAX(seq), 0) + 1 query.
+type singleIntRow struct {
+	value int
+	done  bool
+}
+
+func (r *singleIntRow) Columns() []string { return []string{"seq"} }
+func (r *singleIntRow) Close() error      { return nil }
+
+func (r *singleIntRow) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0] = int64(r.value)
+	r.done = true
+	return nil
+}
+
+// TestSQLStoreAppendEventConcurrentSeqAllocation reproduces many
+// concurrent AppendEvent calls for the same jid and asserts every call
+// succeeds with a distinct, gapless seq when the database serializes
+// transactions the way a single-writer database does (fakeEventStore
+// holds its lock for each transaction's lifetime).
+func TestSQLStoreAppendEventConcurrentSeqAllocation(t *testing.T) {
+	db, store := newFakeSeqDB(t)
+	defer db.Close()
+	sqlStore := NewSQLStore[string](db)
+
+	const n = 20
+	errs := make(chan *fsmErrors.FsmError, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- sqlStore.AppendEvent(context.Background(), JourneyEvent[string]{
+				JID:       "jid-1",
+				FromState: "a",
+				ToState:   "b",
+				Event:     "go",
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AppendEvent failed: %v", err)
+		}
+	}
+
+	seqs := store.seqs["jid-1"]
+	if len(seqs) != n {
+		t.Fatalf("expected %d distinct seqs, got %d: %v", n, len(seqs), seqs)
+	}
+	for i := 1; i <= n; i++ {
+		if !seqs[i] {
+			t.Fatalf("seq %d missing from allocated seqs %v", i, seqs)
+		}
+	}
+}
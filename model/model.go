@@ -0,0 +1,84 @@
+package model
+
+import (
+	"context"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+)
+
+// StateHandler is implemented by callers to drive the side effects of
+// entering (Visit) or re-entering (Revisit) a state.
+type StateHandler interface {
+	Visit(ctx context.Context, jid string, journeyData any, data any) (response any, updatedJourneyData any, nextEvent string, err *fsmErrors.FsmError)
+	Revisit(ctx context.Context, jid string, journeyData any) (response any, updatedJourneyData any, err *fsmErrors.FsmError)
+}
+
+// ParallelBranch names one concurrent sub-journey fanned out by a
+// NextAvailableEvent: Name identifies the branch (and suffixes its
+// child JID), InitialStateName is where it starts executing.
+type ParallelBranch struct {
+	Name             string
+	InitialStateName string
+}
+
+type NextAvailableEvent struct {
+	Event                string
+	DestinationStateName string
+
+	// ParallelBranches, if non-empty, makes this event a fan-out: all
+	// branches run concurrently against a cloned copy of the journey
+	// data, and DestinationStateName becomes the join state visited
+	// once every branch completes.
+	ParallelBranches []ParallelBranch
+}
+
+// StateHook runs immediately before or after a state is entered or
+// exited. BeforeExit and BeforeEnter hooks receive the journey as it
+// stood at the start of the transition; AfterEnter and AfterExit hooks
+// receive it as updated by the entered state's StateHandler.Visit. A
+// hook may transform data for the next stage in the chain.
+// Returning the fsmErrors.FsmError sentinel service.HookAbort ends the
+// transition cleanly without running the remaining hooks or, for a
+// BeforeEnter/BeforeExit hook, the state's StateHandler.
+type StateHook[T any] func(ctx context.Context, journey Journey[T], event string, data any) (any, *fsmErrors.FsmError)
+
+type FsmState[T any] struct {
+	Name                string
+	IsCheckpoint        bool
+	NextScreen          string
+	MetaData            map[string]any
+	StateHandler        StateHandler
+	NextAvailableEvents []NextAvailableEvent
+	BeforeEnter         StateHook[T]
+	AfterEnter          StateHook[T]
+	BeforeExit          StateHook[T]
+	AfterExit           StateHook[T]
+}
+
+type Journey[T any] struct {
+	JID                 string
+	CurrentStage        string
+	LastCheckpointStage string
+	Data                T
+
+	// PendingBranches holds the child JIDs of an in-flight fan-out (see
+	// NextAvailableEvent.ParallelBranches). It is saved before the
+	// branches start and cleared once they've all joined, so a crash
+	// mid-fan-out is visible on the saved journey and a resume request
+	// (FsmRequest.Event == constants.EventNameResume) re-drives the same
+	// fan-out event, reusing whichever branches already finished.
+	PendingBranches []string
+}
+
+type FsmRequest struct {
+	JID   string
+	Event string
+	Data  any
+}
+
+type FsmResponse struct {
+	JID        string
+	Data       any
+	NextScreen string
+	MetaData   map[string]any
+}
@@ -15,20 +15,70 @@ import (
 
 type FsmService[T any] interface {
 	Execute(ctx context.Context, request model.FsmRequest) (response model.FsmResponse, err *fsmErrors.FsmError)
+
+	// Validate walks the registered state graph and reports structural
+	// problems: states unreachable from the initial state, dangling
+	// DestinationStateName references, and checkpoints that cannot
+	// reach the final state.
+	Validate() *fsmErrors.FsmError
+
+	// States returns the full registered state map, keyed by state name.
+	States() map[string]model.FsmState[T]
+	InitialStateName() string
+	FinalStateName() string
+
+	// Subscribe registers an Observer for transition notifications. See
+	// Observer for delivery semantics.
+	Subscribe(observer Observer[T]) (unsubscribe func())
+
+	// RegisterGlobalHook registers fn to run for every event-driven
+	// transition, in addition to any hook configured on the individual
+	// FsmState. Resume and back requests run no hooks.
+	RegisterGlobalHook(kind HookKind, fn model.StateHook[T])
+
+	// History, RewindTo, and Fork require a journeystore.JourneyStore
+	// that also implements journeystore.JourneyHistoryStore; they
+	// return an error otherwise.
+	History(ctx context.Context, jid string) ([]journeystore.JourneyEvent[T], *fsmErrors.FsmError)
+	RewindTo(ctx context.Context, jid string, seq int) (model.FsmResponse, *fsmErrors.FsmError)
+	Fork(ctx context.Context, jid string, seq int) (newJID string, err *fsmErrors.FsmError)
 }
 
 type fsmService[T any] struct {
-	states           map[string]model.FsmState
+	states           map[string]model.FsmState[T]
 	initialStateName string
 	finalStateName   string
 	journeyStore     journeystore.JourneyStore[T]
+	observers        *observerRegistry[T]
+	hooks            *hookRegistry[T]
+	cloner           Cloner[T]
+	merger           Merger[T]
+
+	// branchEntryNames holds the InitialStateName of every declared
+	// ParallelBranch, so Validate can seed reachability from them (they
+	// are only ever entered via a fan-out, never via a regular
+	// NextAvailableEvent edge).
+	branchEntryNames []string
 }
 
-func NewFsmService[T any](initialState model.FsmState, nonInitStates []model.FsmState, journeyStore journeystore.JourneyStore[T]) (FsmService[T], *fsmErrors.FsmError) {
-	fsmStateMap := make(map[string]model.FsmState)
-	var finalStateName string
+// NewFsmService wires up a machine from its states and journey store.
+// cloner and merger are only required if any NextAvailableEvent
+// declares ParallelBranches; pass nil, nil otherwise.
+func NewFsmService[T any](initialState model.FsmState[T], nonInitStates []model.FsmState[T], journeyStore journeystore.JourneyStore[T], cloner Cloner[T], merger Merger[T]) (FsmService[T], *fsmErrors.FsmError) {
+	fsmStateMap := make(map[string]model.FsmState[T])
 	for _, state := range nonInitStates {
 		fsmStateMap[state.Name] = state
+	}
+	fsmStateMap[initialState.Name] = initialState
+
+	branchEntryNames := branchEntryStateNames(fsmStateMap)
+	branchStates := branchOnlyStates(fsmStateMap, branchEntryNames)
+
+	var finalStateName string
+	for _, state := range nonInitStates {
+		if branchStates[state.Name] {
+			continue
+		}
 		if len(state.NextAvailableEvents) == 0 {
 			if finalStateName != "" {
 				return fsmService[T]{}, fsmErrors.InternalSystemError("multiple final states found")
@@ -41,19 +91,36 @@ func NewFsmService[T any](initialState model.FsmState, nonInitStates []model.Fsm
 		return fsmService[T]{}, fsmErrors.InternalSystemError("no final state found")
 	}
 
-	fsmStateMap[initialState.Name] = initialState
+	fs := fsmService[T]{
+		states:           fsmStateMap,
+		journeyStore:     journeyStore,
+		initialStateName: initialState.Name,
+		finalStateName:   finalStateName,
+		observers:        newObserverRegistry[T](),
+		hooks:            newHookRegistry[T](),
+		cloner:           cloner,
+		merger:           merger,
+		branchEntryNames: branchEntryNames,
+	}
+
+	if err := fs.Validate(); err != nil {
+		return fsmService[T]{}, err
+	}
 
-	return fsmService[T]{states: fsmStateMap, journeyStore: journeyStore, initialStateName: initialState.Name, finalStateName: finalStateName}, nil
+	return fs, nil
 }
 
 func (fs fsmService[T]) Execute(ctx context.Context, request model.FsmRequest) (response model.FsmResponse, err *fsmErrors.FsmError) {
 	var journey model.Journey[T]
-
-	var currentState, nextState, lastExecutedState model.FsmState
+	var lastExecutedState model.FsmState[T]
 	var nextStateData any
 	var nextEvent string
 
-	var finishStateTransition bool
+	defer func() {
+		if err != nil {
+			fs.notifyError(ctx, journey, err)
+		}
+	}()
 
 	if request.JID != "" {
 		journey, err = fs.journeyStore.Get(ctx, request.JID)
@@ -80,73 +147,253 @@ func (fs fsmService[T]) Execute(ctx context.Context, request model.FsmRequest) (
 				_ = fs.journeyStore.Delete(ctx, journey.JID)
 			}
 		}()
-		lastExecutedState, err = fs.getState(journey.CurrentStage)
-		if err != nil {
-			return
-		}
-		if nextEvent == constants.EventNameTransitionComplete {
-			finishStateTransition = true
-		}
 	}
 
-	for !finishStateTransition {
-		currentState, err = fs.getState(journey.CurrentStage)
+	journey, lastExecutedState, nextStateData, err = fs.runUntilComplete(ctx, journey, nextStateData, nextEvent)
+	if err != nil {
+		return
+	}
+
+	err = fs.journeyStore.Save(ctx, journey)
+	if err != nil {
+		return
+	}
+
+	if journey.CurrentStage == fs.finalStateName {
+		fs.notifyJourneyCompleted(ctx, journey)
+	}
+
+	return fs.loadFsmResponse(journey, lastExecutedState, nextStateData), nil
+}
+
+// runUntilComplete drives a journey through handleStateVisit (or, for a
+// fan-out event, handleFanOut) until a StateHandler reports
+// constants.EventNameTransitionComplete, then resolves the state it
+// stopped in. It underlies both Execute and each fan-out branch in
+// handleFanOut.
+func (fs fsmService[T]) runUntilComplete(ctx context.Context, journey model.Journey[T], data any, event string) (model.Journey[T], model.FsmState[T], any, *fsmErrors.FsmError) {
+	for event != constants.EventNameTransitionComplete {
+		currentState, err := fs.getState(journey.CurrentStage)
 		if err != nil {
-			return
+			return model.Journey[T]{}, model.FsmState[T]{}, nil, err
 		}
-		nextState, err = fs.getNextState(currentState, nextEvent)
+		triggerEvent := event
+		nextAvailableEvent, err := fs.getNextAvailableEvent(currentState, event)
 		if err != nil {
-			return
+			return model.Journey[T]{}, model.FsmState[T]{}, nil, err
 		}
-		journey, nextStateData, nextEvent, err = fs.handleStateVisit(ctx, nextState, journey, nextStateData)
+		nextState, err := fs.getState(nextAvailableEvent.DestinationStateName)
 		if err != nil {
-			return
+			return model.Journey[T]{}, model.FsmState[T]{}, nil, err
 		}
-		lastExecutedState = nextState
-		if nextEvent == constants.EventNameTransitionComplete {
-			finishStateTransition = true
+
+		if len(nextAvailableEvent.ParallelBranches) > 0 {
+			journey, data, event, err = fs.handleFanOut(ctx, currentState, nextState, nextAvailableEvent.ParallelBranches, journey, data, triggerEvent)
+		} else {
+			journey, data, event, err = fs.handleStateVisit(ctx, currentState, nextState, journey, data, triggerEvent)
+		}
+		if err != nil {
+			return model.Journey[T]{}, model.FsmState[T]{}, nil, err
 		}
 	}
 
-	err = fs.journeyStore.Save(ctx, journey)
+	lastExecutedState, err := fs.getState(journey.CurrentStage)
 	if err != nil {
-		return
+		return model.Journey[T]{}, model.FsmState[T]{}, nil, err
 	}
+	return journey, lastExecutedState, data, nil
+}
 
-	return fs.loadFsmResponse(journey, lastExecutedState, nextStateData), nil
+func (fs fsmService[T]) States() map[string]model.FsmState[T] {
+	return fs.states
+}
+
+func (fs fsmService[T]) InitialStateName() string {
+	return fs.initialStateName
 }
 
-func (fs fsmService[T]) getState(stateName string) (model.FsmState, *fsmErrors.FsmError) {
+func (fs fsmService[T]) FinalStateName() string {
+	return fs.finalStateName
+}
+
+func (fs fsmService[T]) Validate() *fsmErrors.FsmError {
+	reachable := map[string]bool{fs.initialStateName: true}
+	queue := []string{fs.initialStateName}
+	for _, name := range fs.branchEntryNames {
+		if !reachable[name] {
+			reachable[name] = true
+			queue = append(queue, name)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		state, ok := fs.states[name]
+		if !ok {
+			return fsmErrors.InternalSystemError(fmt.Sprintf("state %s is referenced but not registered", name))
+		}
+		for _, next := range state.NextAvailableEvents {
+			if _, ok := fs.states[next.DestinationStateName]; !ok {
+				return fsmErrors.InternalSystemError(fmt.Sprintf("state %s has event %s pointing to unknown state %s", name, next.Event, next.DestinationStateName))
+			}
+			if !reachable[next.DestinationStateName] {
+				reachable[next.DestinationStateName] = true
+				queue = append(queue, next.DestinationStateName)
+			}
+		}
+	}
+
+	for name := range fs.states {
+		if !reachable[name] {
+			return fsmErrors.InternalSystemError(fmt.Sprintf("state %s is unreachable from the initial state %s", name, fs.initialStateName))
+		}
+	}
+
+	for name, state := range fs.states {
+		if state.IsCheckpoint && !fs.canReach(name, fs.finalStateName) {
+			return fsmErrors.InternalSystemError(fmt.Sprintf("final state %s is not reachable from checkpoint %s", fs.finalStateName, name))
+		}
+	}
+
+	return nil
+}
+
+func (fs fsmService[T]) canReach(from, to string) bool {
+	if from == to {
+		return true
+	}
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		state, ok := fs.states[name]
+		if !ok {
+			continue
+		}
+		for _, next := range state.NextAvailableEvents {
+			if next.DestinationStateName == to {
+				return true
+			}
+			if !visited[next.DestinationStateName] {
+				visited[next.DestinationStateName] = true
+				queue = append(queue, next.DestinationStateName)
+			}
+		}
+	}
+	return false
+}
+
+func (fs fsmService[T]) getState(stateName string) (model.FsmState[T], *fsmErrors.FsmError) {
 	state, ok := fs.states[stateName]
 	if !ok {
-		return model.FsmState{}, fsmErrors.InternalSystemError("cannot find next state")
+		return model.FsmState[T]{}, fsmErrors.InternalSystemError("cannot find next state")
 	}
 	return state, nil
 }
 
-func (fs fsmService[T]) getNextState(currentState model.FsmState, event string) (model.FsmState, *fsmErrors.FsmError) {
+func (fs fsmService[T]) getNextAvailableEvent(currentState model.FsmState[T], event string) (model.NextAvailableEvent, *fsmErrors.FsmError) {
 	for _, nextAvailableEvent := range currentState.NextAvailableEvents {
 		if nextAvailableEvent.Event == event {
-			return fs.getState(nextAvailableEvent.DestinationStateName)
+			return nextAvailableEvent, nil
 		}
 	}
-	return model.FsmState{}, fsmErrors.ByPassError(fmt.Sprintf("invalid event %s for state %s", event, currentState.Name))
+	return model.NextAvailableEvent{}, fsmErrors.ByPassError(fmt.Sprintf("invalid event %s for state %s", event, currentState.Name))
 }
 
-func (fs fsmService[T]) handleStateVisit(ctx context.Context, state model.FsmState, journey model.Journey[T], data any) (model.Journey[T], any, string, *fsmErrors.FsmError) {
-	resp, updatedJourneyData, nextEvent, err := state.StateHandler.Visit(ctx, journey.JID, journey.Data, data)
+func (fs fsmService[T]) getNextState(currentState model.FsmState[T], event string) (model.FsmState[T], *fsmErrors.FsmError) {
+	nextAvailableEvent, err := fs.getNextAvailableEvent(currentState, event)
+	if err != nil {
+		return model.FsmState[T]{}, err
+	}
+	return fs.getState(nextAvailableEvent.DestinationStateName)
+}
+
+func (fs fsmService[T]) handleStateVisit(ctx context.Context, currentState, state model.FsmState[T], journey model.Journey[T], data any, triggerEvent string) (model.Journey[T], any, string, *fsmErrors.FsmError) {
+	fromState := journey.CurrentStage
+	hasCurrentState := currentState.Name != ""
+
+	if hasCurrentState {
+		result, aborted, err := fs.runHookChain(ctx, currentState.BeforeExit, HookKindBeforeExit, journey, triggerEvent, data)
+		if err != nil {
+			return model.Journey[T]{}, nil, "", err
+		}
+		if aborted {
+			return journey, result, constants.EventNameTransitionComplete, nil
+		}
+		data = result
+	}
+
+	result, aborted, err := fs.runHookChain(ctx, state.BeforeEnter, HookKindBeforeEnter, journey, triggerEvent, data)
 	if err != nil {
 		return model.Journey[T]{}, nil, "", err
 	}
+	if aborted {
+		// The journey has arrived at state even though its StateHandler
+		// never ran, so CurrentStage must reflect that - otherwise a
+		// first-visit abort (hasCurrentState == false, e.g. an
+		// authorization check on the initial state or a fan-out branch's
+		// entry state) would leave CurrentStage empty and strand the
+		// journey on the next Execute/resume. Since a real transition
+		// happened (fromState -> state.Name), it must be recorded the
+		// same as any other transition, or observers and the history
+		// store would disagree with the journey actually saved.
+		journey.CurrentStage = state.Name
+		if state.IsCheckpoint {
+			journey.LastCheckpointStage = state.Name
+		}
+		fs.finishTransition(ctx, journey, fromState, triggerEvent, state.Name, data, result)
+		return journey, result, constants.EventNameTransitionComplete, nil
+	}
+	data = result
+
+	resp, updatedJourneyData, nextEvent, visitErr := state.StateHandler.Visit(ctx, journey.JID, journey.Data, data)
+	if visitErr != nil {
+		return model.Journey[T]{}, nil, "", visitErr
+	}
 	journey.Data = updatedJourneyData.(T)
 	journey.CurrentStage = state.Name
 	if state.IsCheckpoint {
 		journey.LastCheckpointStage = state.Name
 	}
+
+	resp, aborted, err = fs.runHookChain(ctx, state.AfterEnter, HookKindAfterEnter, journey, triggerEvent, resp)
+	if err != nil {
+		return model.Journey[T]{}, nil, "", err
+	}
+	if aborted {
+		fs.finishTransition(ctx, journey, fromState, triggerEvent, state.Name, data, resp)
+		return journey, resp, constants.EventNameTransitionComplete, nil
+	}
+
+	if hasCurrentState {
+		resp, aborted, err = fs.runHookChain(ctx, currentState.AfterExit, HookKindAfterExit, journey, triggerEvent, resp)
+		if err != nil {
+			return model.Journey[T]{}, nil, "", err
+		}
+		if aborted {
+			fs.finishTransition(ctx, journey, fromState, triggerEvent, state.Name, data, resp)
+			return journey, resp, constants.EventNameTransitionComplete, nil
+		}
+	}
+
+	fs.finishTransition(ctx, journey, fromState, triggerEvent, state.Name, data, resp)
 	return journey, resp, nextEvent, nil
 }
 
-func (fs fsmService[T]) handleStateRevisit(ctx context.Context, state model.FsmState, journey model.Journey[T]) (model.Journey[T], any, *fsmErrors.FsmError) {
+// finishTransition notifies observers and, if the journey store
+// supports it, appends a JourneyEvent recording this step.
+func (fs fsmService[T]) finishTransition(ctx context.Context, journey model.Journey[T], fromState, event, toState string, inputData, resultData any) {
+	if fromState != "" {
+		fs.notifyStateExited(ctx, journey, fromState, event, toState)
+	}
+	fs.notifyStateEntered(ctx, journey, fromState, event, toState)
+	fs.recordHistory(ctx, journey, fromState, toState, event, inputData, resultData)
+}
+
+func (fs fsmService[T]) handleStateRevisit(ctx context.Context, state model.FsmState[T], journey model.Journey[T], triggerEvent string) (model.Journey[T], any, *fsmErrors.FsmError) {
+	fromState := journey.CurrentStage
 	resp, updatedJourneyData, err := state.StateHandler.Revisit(ctx, journey.JID, journey.Data)
 	if err != nil {
 		return model.Journey[T]{}, nil, err
@@ -156,15 +403,24 @@ func (fs fsmService[T]) handleStateRevisit(ctx context.Context, state model.FsmS
 		journey.LastCheckpointStage = state.Name
 	}
 	journey.Data = updatedJourneyData.(T)
+	fs.finishTransition(ctx, journey, fromState, triggerEvent, state.Name, nil, resp)
 	return journey, resp, nil
 }
 
 func (fs fsmService[T]) handleResumeJourney(ctx context.Context, journey model.Journey[T]) (model.FsmResponse, *fsmErrors.FsmError) {
+	if len(journey.PendingBranches) > 0 {
+		return fs.resumeFanOut(ctx, journey)
+	}
 	state, err := fs.getState(journey.LastCheckpointStage)
 	if err != nil {
 		return model.FsmResponse{}, err
 	}
-	return fs.revisitAndSave(ctx, journey, state)
+	journey, response, err := fs.revisitAndSave(ctx, journey, state, constants.EventNameResume)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+	fs.notifyJourneyResumed(ctx, journey)
+	return response, nil
 }
 
 func (fs fsmService[T]) handleBackJourney(ctx context.Context, journey model.Journey[T]) (model.FsmResponse, *fsmErrors.FsmError) {
@@ -176,7 +432,11 @@ func (fs fsmService[T]) handleBackJourney(ctx context.Context, journey model.Jou
 	if err != nil {
 		return model.FsmResponse{}, err
 	}
-	return fs.revisitAndSave(ctx, journey, nextState)
+	_, response, err := fs.revisitAndSave(ctx, journey, nextState, constants.EventNameBack)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+	return response, nil
 }
 
 func (fs fsmService[T]) startNewJourney(ctx context.Context, data any, event string) (model.Journey[T], any, string, *fsmErrors.FsmError) {
@@ -192,7 +452,8 @@ func (fs fsmService[T]) startNewJourney(ctx context.Context, data any, event str
 		return model.Journey[T]{}, nil, "", err
 	}
 	jid := journey.JID
-	journey, resp, nextEvent, err := fs.handleStateVisit(ctx, initState, journey, data)
+	fs.notifyJourneyCreated(ctx, journey)
+	journey, resp, nextEvent, err := fs.handleStateVisit(ctx, model.FsmState[T]{}, initState, journey, data, event)
 	if err != nil {
 		_ = fs.journeyStore.Delete(ctx, jid)
 		return model.Journey[T]{}, nil, "", err
@@ -201,19 +462,19 @@ func (fs fsmService[T]) startNewJourney(ctx context.Context, data any, event str
 	return journey, resp, nextEvent, nil
 }
 
-func (fs fsmService[T]) revisitAndSave(ctx context.Context, journey model.Journey[T], state model.FsmState) (model.FsmResponse, *fsmErrors.FsmError) {
-	journey, resp, err := fs.handleStateRevisit(ctx, state, journey)
+func (fs fsmService[T]) revisitAndSave(ctx context.Context, journey model.Journey[T], state model.FsmState[T], triggerEvent string) (model.Journey[T], model.FsmResponse, *fsmErrors.FsmError) {
+	journey, resp, err := fs.handleStateRevisit(ctx, state, journey, triggerEvent)
 	if err != nil {
-		return model.FsmResponse{}, err
+		return model.Journey[T]{}, model.FsmResponse{}, err
 	}
 	err = fs.journeyStore.Save(ctx, journey)
 	if err != nil {
-		return model.FsmResponse{}, err
+		return model.Journey[T]{}, model.FsmResponse{}, err
 	}
-	return fs.loadFsmResponse(journey, state, resp), nil
+	return journey, fs.loadFsmResponse(journey, state, resp), nil
 }
 
-func (fs fsmService[T]) loadFsmResponse(journey model.Journey[T], state model.FsmState, response any) model.FsmResponse {
+func (fs fsmService[T]) loadFsmResponse(journey model.Journey[T], state model.FsmState[T], response any) model.FsmResponse {
 	return model.FsmResponse{
 		JID:        journey.JID,
 		Data:       response,
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+
+	"testing"
+
+	"github.com/thevibegod/fsm/constants"
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	journeystore "github.com/thevibegod/fsm/journey_store"
+	"github.com/thevibegod/fsm/model"
+)
+
+// TestValidateRejectsUnreachableState checks that a state registered
+// alongside the machine but never referenced by any NextAvailableEvent
+// (and not a fan-out branch entry) fails Validate instead of silently
+// sitting dead in the graph.
+func TestValidateRejectsUnreachableState(t *testing.T) {
+	initial := model.FsmState[testData]{
+		Name:                "start",
+		StateHandler:        stepHandler("start", constants.EventNameTransitionComplete),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "go", DestinationStateName: "final"}},
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+	// orphan has an outgoing edge so it is not taken for a second final
+	// state, which NewFsmService would reject before Validate ever ran.
+	orphan := model.FsmState[testData]{
+		Name:                "orphan",
+		StateHandler:        stepHandler("orphan", constants.EventNameTransitionComplete),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "go", DestinationStateName: "final"}},
+	}
+
+	_, err := NewFsmService[testData](initial, []model.FsmState[testData]{final, orphan}, journeystore.NewMemoryStore[testData](), nil, nil)
+	if err == nil {
+		t.Fatal("expected Validate to reject a state unreachable from the initial state")
+	}
+}
+
+// TestValidateRejectsDanglingDestination checks that a NextAvailableEvent
+// pointing at a state name that was never registered fails Validate
+// instead of surfacing as a runtime "cannot find next state" error the
+// first time a journey reaches it.
+func TestValidateRejectsDanglingDestination(t *testing.T) {
+	initial := model.FsmState[testData]{
+		Name:                "start",
+		StateHandler:        stepHandler("start", "go"),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "go", DestinationStateName: "nowhere"}},
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	_, err := NewFsmService[testData](initial, []model.FsmState[testData]{final}, journeystore.NewMemoryStore[testData](), nil, nil)
+	if err == nil {
+		t.Fatal("expected Validate to reject a NextAvailableEvent pointing at an unregistered state")
+	}
+}
+
+// TestValidateRejectsCheckpointThatCannotReachFinal checks that a
+// checkpoint state whose only outgoing edges dead-end before the final
+// state fails Validate - a journey resumed from that checkpoint could
+// otherwise never complete.
+func TestValidateRejectsCheckpointThatCannotReachFinal(t *testing.T) {
+	initial := model.FsmState[testData]{
+		Name:         "start",
+		StateHandler: stepHandler("start", "go"),
+		NextAvailableEvents: []model.NextAvailableEvent{
+			{Event: "go", DestinationStateName: "deadEnd"},
+			{Event: "skip", DestinationStateName: "final"},
+		},
+		IsCheckpoint: true,
+	}
+	// deadEnd is a checkpoint whose only edge loops back to itself, so it
+	// never reaches final and is not taken for a second final state.
+	deadEnd := model.FsmState[testData]{
+		Name:                "deadEnd",
+		StateHandler:        stepHandler("deadEnd", constants.EventNameTransitionComplete),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "loop", DestinationStateName: "deadEnd"}},
+		IsCheckpoint:        true,
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	_, err := NewFsmService[testData](initial, []model.FsmState[testData]{deadEnd, final}, journeystore.NewMemoryStore[testData](), nil, nil)
+	if err == nil {
+		t.Fatal("expected Validate to reject a checkpoint that cannot reach the final state")
+	}
+}
+
+// TestGetNextAvailableEventReturnsByPassError checks that an event with
+// no matching NextAvailableEvent on the current state is reported as a
+// ByPassError, not an internal error, since it reflects a caller mistake
+// rather than a broken machine.
+func TestGetNextAvailableEventReturnsByPassError(t *testing.T) {
+	initial := model.FsmState[testData]{
+		Name:                "start",
+		StateHandler:        stepHandler("start", constants.EventNameTransitionComplete),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "go", DestinationStateName: "final"}},
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	fs, err := NewFsmService[testData](initial, []model.FsmState[testData]{final}, journeystore.NewMemoryStore[testData](), nil, nil)
+	if err != nil {
+		t.Fatalf("NewFsmService: %v", err)
+	}
+
+	resp, execErr := fs.Execute(context.Background(), model.FsmRequest{Event: constants.EventNameStart})
+	if execErr != nil {
+		t.Fatalf("Execute: %v", execErr)
+	}
+
+	_, execErr = fs.Execute(context.Background(), model.FsmRequest{JID: resp.JID, Event: "no-such-event"})
+	if execErr == nil {
+		t.Fatal("expected an invalid event to be rejected")
+	}
+	if execErr.Code != fsmErrors.ByPassError("").Code {
+		t.Fatalf("expected a ByPassError for an invalid event, got code %q", execErr.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	journeystore "github.com/thevibegod/fsm/journey_store"
+	"github.com/thevibegod/fsm/model"
+)
+
+func (fs fsmService[T]) historyStore() (journeystore.JourneyHistoryStore[T], *fsmErrors.FsmError) {
+	historyStore, ok := fs.journeyStore.(journeystore.JourneyHistoryStore[T])
+	if !ok {
+		return nil, fsmErrors.InternalSystemError("journey store does not implement journeystore.JourneyHistoryStore")
+	}
+	return historyStore, nil
+}
+
+// recordHistory best-effort appends a JourneyEvent for this step. It is
+// a no-op when the configured journeyStore doesn't implement
+// journeystore.JourneyHistoryStore, and it never fails the transition
+// that produced it - a history write failing shouldn't take down the
+// journey itself.
+func (fs fsmService[T]) recordHistory(ctx context.Context, journey model.Journey[T], fromState, toState, event string, inputData, resultData any) {
+	historyStore, ok := fs.journeyStore.(journeystore.JourneyHistoryStore[T])
+	if !ok {
+		return
+	}
+	_ = historyStore.AppendEvent(ctx, journeystore.JourneyEvent[T]{
+		JID:             journey.JID,
+		FromState:       fromState,
+		ToState:         toState,
+		Event:           event,
+		InputData:       inputData,
+		ResultData:      resultData,
+		JourneyDataDiff: journey.Data,
+	})
+}
+
+// History returns the full, ordered event history for jid.
+func (fs fsmService[T]) History(ctx context.Context, jid string) ([]journeystore.JourneyEvent[T], *fsmErrors.FsmError) {
+	historyStore, err := fs.historyStore()
+	if err != nil {
+		return nil, err
+	}
+	return historyStore.Events(ctx, jid)
+}
+
+// RewindTo reconstructs the journey as it stood after the step with the
+// given seq by replaying its recorded events, without mutating the
+// stored journey.
+func (fs fsmService[T]) RewindTo(ctx context.Context, jid string, seq int) (model.FsmResponse, *fsmErrors.FsmError) {
+	journey, state, err := fs.replayTo(ctx, jid, seq)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+	return fs.loadFsmResponse(journey, state, journey.Data), nil
+}
+
+// Fork creates a new journey branched from jid's state as of seq, saved
+// under its own JID so it can progress independently of the original.
+func (fs fsmService[T]) Fork(ctx context.Context, jid string, seq int) (string, *fsmErrors.FsmError) {
+	journey, _, err := fs.replayTo(ctx, jid, seq)
+	if err != nil {
+		return "", err
+	}
+
+	forked, err := fs.journeyStore.Create(ctx)
+	if err != nil {
+		return "", err
+	}
+	journey.JID = forked.JID
+	if err := fs.journeyStore.Save(ctx, journey); err != nil {
+		return "", err
+	}
+	return journey.JID, nil
+}
+
+// replayTo folds jid's recorded events up to and including seq into a
+// Journey, the way handleStateVisit/handleStateRevisit built it live.
+func (fs fsmService[T]) replayTo(ctx context.Context, jid string, seq int) (model.Journey[T], model.FsmState[T], *fsmErrors.FsmError) {
+	historyStore, err := fs.historyStore()
+	if err != nil {
+		return model.Journey[T]{}, model.FsmState[T]{}, err
+	}
+	events, err := historyStore.Events(ctx, jid)
+	if err != nil {
+		return model.Journey[T]{}, model.FsmState[T]{}, err
+	}
+
+	var journey model.Journey[T]
+	var found bool
+	for _, event := range events {
+		if event.Seq > seq {
+			break
+		}
+		journey.JID = jid
+		journey.CurrentStage = event.ToState
+		journey.Data = event.JourneyDataDiff
+		if state, ok := fs.states[event.ToState]; ok && state.IsCheckpoint {
+			journey.LastCheckpointStage = event.ToState
+		}
+		found = true
+	}
+	if !found {
+		return model.Journey[T]{}, model.FsmState[T]{}, fsmErrors.InternalSystemError("no recorded event at or before seq for journey " + jid)
+	}
+
+	state, err := fs.getState(journey.CurrentStage)
+	if err != nil {
+		return model.Journey[T]{}, model.FsmState[T]{}, err
+	}
+	return journey, state, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thevibegod/fsm/constants"
+	journeystore "github.com/thevibegod/fsm/journey_store"
+	"github.com/thevibegod/fsm/model"
+)
+
+func newHistoryTestService(t *testing.T) (FsmService[testData], *journeystore.MemoryStore[testData], string) {
+	t.Helper()
+	initial := model.FsmState[testData]{
+		Name:         "start",
+		StateHandler: stepHandler("start", "go"),
+		NextAvailableEvents: []model.NextAvailableEvent{
+			{Event: "go", DestinationStateName: "middle"},
+		},
+	}
+	middle := model.FsmState[testData]{
+		Name:         "middle",
+		StateHandler: stepHandler("middle", "finish"),
+		NextAvailableEvents: []model.NextAvailableEvent{
+			{Event: "finish", DestinationStateName: "final"},
+		},
+		IsCheckpoint: true,
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	journeyStore := journeystore.NewMemoryStore[testData]()
+	fs, err := NewFsmService[testData](initial, []model.FsmState[testData]{middle, final}, journeyStore, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFsmService: %v", err)
+	}
+
+	resp, execErr := fs.Execute(context.Background(), model.FsmRequest{Event: constants.EventNameStart})
+	if execErr != nil {
+		t.Fatalf("Execute: %v", execErr)
+	}
+	return fs, journeyStore, resp.JID
+}
+
+// TestHistoryReturnsEventsInOrder checks that History returns one event
+// per transition, in the order they happened, once a journey has run to
+// completion.
+func TestHistoryReturnsEventsInOrder(t *testing.T) {
+	fs, _, jid := newHistoryTestService(t)
+
+	events, err := fs.History(context.Background(), jid)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 recorded events (->start->middle->final), got %d: %+v", len(events), events)
+	}
+	want := []string{"start", "middle", "final"}
+	for i, event := range events {
+		if event.ToState != want[i] {
+			t.Fatalf("event %d: expected ToState %q, got %q", i, want[i], event.ToState)
+		}
+		if event.Seq != i+1 {
+			t.Fatalf("event %d: expected Seq %d, got %d", i, i+1, event.Seq)
+		}
+	}
+}
+
+// TestRewindToReconstructsPastState checks that RewindTo replays events
+// up to and including seq and reports the journey as it stood then,
+// without touching the journey actually saved in the store.
+func TestRewindToReconstructsPastState(t *testing.T) {
+	fs, journeyStore, jid := newHistoryTestService(t)
+
+	resp, err := fs.RewindTo(context.Background(), jid, 1)
+	if err != nil {
+		t.Fatalf("RewindTo: %v", err)
+	}
+	if resp.JID != jid {
+		t.Fatalf("expected RewindTo response JID %q, got %q", jid, resp.JID)
+	}
+
+	current, getErr := journeyStore.Get(context.Background(), jid)
+	if getErr != nil {
+		t.Fatalf("journeyStore.Get: %v", getErr)
+	}
+	if current.CurrentStage != "final" {
+		t.Fatalf("RewindTo must not mutate the stored journey, but CurrentStage is now %q", current.CurrentStage)
+	}
+}
+
+// TestForkCreatesIndependentJourney checks that Fork saves the replayed
+// state under a brand new JID, leaving the original journey untouched.
+func TestForkCreatesIndependentJourney(t *testing.T) {
+	fs, journeyStore, jid := newHistoryTestService(t)
+
+	forkedJID, err := fs.Fork(context.Background(), jid, 2)
+	if err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+	if forkedJID == jid {
+		t.Fatalf("expected Fork to allocate a new JID distinct from %q", jid)
+	}
+
+	forked, getErr := journeyStore.Get(context.Background(), forkedJID)
+	if getErr != nil {
+		t.Fatalf("journeyStore.Get(forked): %v", getErr)
+	}
+	if forked.CurrentStage != "middle" {
+		t.Fatalf("expected forked journey to resume at %q, got %q", "middle", forked.CurrentStage)
+	}
+
+	original, getErr := journeyStore.Get(context.Background(), jid)
+	if getErr != nil {
+		t.Fatalf("journeyStore.Get(original): %v", getErr)
+	}
+	if original.CurrentStage != "final" {
+		t.Fatalf("Fork must not mutate the original journey, but CurrentStage is now %q", original.CurrentStage)
+	}
+}
+
+// TestHistoryRequiresJourneyHistoryStore checks that History reports an
+// error, rather than panicking, when the configured JourneyStore doesn't
+// also implement JourneyHistoryStore.
+func TestHistoryRequiresJourneyHistoryStore(t *testing.T) {
+	initial := model.FsmState[testData]{
+		Name:                "start",
+		StateHandler:        stepHandler("start", "go"),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "go", DestinationStateName: "final"}},
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	fs, err := NewFsmService[testData](initial, []model.FsmState[testData]{final}, plainJourneyStore[testData]{journeystore.NewMemoryStore[testData]()}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFsmService: %v", err)
+	}
+
+	if _, histErr := fs.History(context.Background(), "anything"); histErr == nil {
+		t.Fatal("expected History to error when the journey store has no history support")
+	}
+}
+
+// plainJourneyStore wraps a journeystore.JourneyStore without exposing
+// its JourneyHistoryStore methods, so it can stand in for a store
+// implementation that never implemented history support.
+type plainJourneyStore[T any] struct {
+	journeystore.JourneyStore[T]
+}
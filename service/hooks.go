@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	"github.com/thevibegod/fsm/model"
+)
+
+// HookKind identifies which point in a transition a hook runs at.
+type HookKind int
+
+const (
+	HookKindBeforeExit HookKind = iota
+	HookKindBeforeEnter
+	HookKindAfterEnter
+	HookKindAfterExit
+)
+
+// HookAbort is a sentinel *fsmErrors.FsmError a hook can return to end
+// the transition chain cleanly, as if the state's StateHandler had
+// returned constants.EventNameTransitionComplete. It is not treated as a
+// failure: the transition is saved and returned to the caller as-is.
+var HookAbort = fsmErrors.ByPassError("hook aborted transition chain")
+
+type hookRegistry[T any] struct {
+	mu     sync.RWMutex
+	global map[HookKind][]model.StateHook[T]
+}
+
+func newHookRegistry[T any]() *hookRegistry[T] {
+	return &hookRegistry[T]{global: make(map[HookKind][]model.StateHook[T])}
+}
+
+func (r *hookRegistry[T]) register(kind HookKind, fn model.StateHook[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.global[kind] = append(r.global[kind], fn)
+}
+
+func (r *hookRegistry[T]) globalHooks(kind HookKind) []model.StateHook[T] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]model.StateHook[T]{}, r.global[kind]...)
+}
+
+// RegisterGlobalHook registers fn to run, for every event-driven
+// transition, at the given HookKind - in addition to any hook configured
+// on the individual FsmState. Resume and back requests, which re-enter a
+// state through StateHandler.Revisit, run no hooks.
+func (fs fsmService[T]) RegisterGlobalHook(kind HookKind, fn model.StateHook[T]) {
+	fs.hooks.register(kind, fn)
+}
+
+// runHookChain runs the per-state hook (if any) followed by every
+// global hook registered for kind, threading data through each in turn.
+// It reports whether the chain was ended early via HookAbort.
+func (fs fsmService[T]) runHookChain(ctx context.Context, stateHook model.StateHook[T], kind HookKind, journey model.Journey[T], event string, data any) (any, bool, *fsmErrors.FsmError) {
+	chain := fs.hooks.globalHooks(kind)
+	if stateHook != nil {
+		chain = append([]model.StateHook[T]{stateHook}, chain...)
+	}
+
+	for _, hook := range chain {
+		result, err := hook(ctx, journey, event, data)
+		if err == HookAbort {
+			return result, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		data = result
+	}
+	return data, false, nil
+}
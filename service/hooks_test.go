@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thevibegod/fsm/constants"
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	journeystore "github.com/thevibegod/fsm/journey_store"
+	"github.com/thevibegod/fsm/model"
+)
+
+// TestBeforeEnterAbortOnFirstVisitSettlesCurrentStage reproduces
+// aborting the initial state's BeforeEnter hook - an authorization check
+// on entry, per the request's own use case - and checks that the
+// transition still ends cleanly with CurrentStage pointing at the state
+// that was being entered, rather than leaving it empty and stranding the
+// journey on its next Execute call.
+func TestBeforeEnterAbortOnFirstVisitSettlesCurrentStage(t *testing.T) {
+	visited := false
+	initial := model.FsmState[testData]{
+		Name: "start",
+		BeforeEnter: func(ctx context.Context, journey model.Journey[testData], event string, data any) (any, *fsmErrors.FsmError) {
+			return data, HookAbort
+		},
+		StateHandler: funcHandler{visit: func(ctx context.Context, jid string, journeyData, data any) (any, any, string, *fsmErrors.FsmError) {
+			visited = true
+			return data, journeyData, constants.EventNameTransitionComplete, nil
+		}},
+		IsCheckpoint:        true,
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "go", DestinationStateName: "final"}},
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	journeyStore := journeystore.NewMemoryStore[testData]()
+	fs, err := NewFsmService[testData](initial, []model.FsmState[testData]{final}, journeyStore, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFsmService: %v", err)
+	}
+
+	resp, execErr := fs.Execute(context.Background(), model.FsmRequest{Event: constants.EventNameStart})
+	if execErr != nil {
+		t.Fatalf("Execute returned an error on an aborted BeforeEnter: %v", execErr)
+	}
+	if visited {
+		t.Fatalf("StateHandler.Visit ran despite BeforeEnter aborting the transition")
+	}
+
+	journey, getErr := journeyStore.Get(context.Background(), resp.JID)
+	if getErr != nil {
+		t.Fatalf("journeyStore.Get: %v", getErr)
+	}
+	if journey.CurrentStage != "start" {
+		t.Fatalf("expected CurrentStage %q after an aborted BeforeEnter, got %q", "start", journey.CurrentStage)
+	}
+	if journey.LastCheckpointStage != "start" {
+		t.Fatalf("expected LastCheckpointStage %q after an aborted BeforeEnter on a checkpoint state, got %q", "start", journey.LastCheckpointStage)
+	}
+
+	// The journey must still be usable: a later Execute against the same
+	// JID has to find a registered state for CurrentStage rather than
+	// failing with an internal "cannot find next state" error.
+	if _, execErr := fs.Execute(context.Background(), model.FsmRequest{JID: resp.JID, Event: "go"}); execErr != nil {
+		t.Fatalf("Execute on the resumed journey failed: %v", execErr)
+	}
+}
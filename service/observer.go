@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/thevibegod/fsm/model"
+)
+
+// observerQueueSize bounds the number of pending notifications per
+// subscriber; once full, new notifications are dropped rather than
+// blocking the transition that produced them.
+const observerQueueSize = 256
+
+// observerPoolSize is the number of workers draining a single
+// subscriber's queue concurrently.
+const observerPoolSize = 4
+
+// Observer receives lifecycle notifications for a FsmService. All
+// methods are invoked asynchronously on a worker pool dedicated to the
+// subscriber that registered the Observer, so a slow or misbehaving
+// Observer cannot stall Execute. An error returned from any callback
+// other than OnError is routed to that same Observer's OnError hook; it
+// never fails the transition that triggered it.
+type Observer[T any] interface {
+	OnStateEntered(ctx context.Context, journey model.Journey[T], fromState, event, toState string) error
+	OnStateExited(ctx context.Context, journey model.Journey[T], fromState, event, toState string) error
+	OnJourneyCreated(ctx context.Context, journey model.Journey[T]) error
+	OnJourneyResumed(ctx context.Context, journey model.Journey[T]) error
+	OnJourneyCompleted(ctx context.Context, journey model.Journey[T]) error
+	OnError(ctx context.Context, journey model.Journey[T], err error)
+}
+
+type observerTask func()
+
+// subscription runs the tasks for one subscriber on a bounded pool of
+// workers so its queue depth - not Execute's call stack - is what bears
+// the cost of a slow Observer.
+type subscription[T any] struct {
+	observer Observer[T]
+	tasks    chan observerTask
+	done     chan struct{}
+}
+
+func newSubscription[T any](observer Observer[T]) *subscription[T] {
+	sub := &subscription[T]{
+		observer: observer,
+		tasks:    make(chan observerTask, observerQueueSize),
+		done:     make(chan struct{}),
+	}
+	for i := 0; i < observerPoolSize; i++ {
+		go sub.run()
+	}
+	return sub
+}
+
+func (s *subscription[T]) run() {
+	for {
+		select {
+		case task, ok := <-s.tasks:
+			if !ok {
+				return
+			}
+			task()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *subscription[T]) enqueue(task observerTask) {
+	select {
+	case s.tasks <- task:
+	default:
+		// subscriber is backed up; drop the notification instead of
+		// stalling the caller.
+	}
+}
+
+func (s *subscription[T]) stop() {
+	close(s.done)
+}
+
+// observerRegistry tracks the live subscriptions for a single
+// fsmService. It is held behind a pointer on fsmService so it survives
+// copies of the (value-receiver) fsmService struct.
+type observerRegistry[T any] struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]*subscription[T]
+}
+
+func newObserverRegistry[T any]() *observerRegistry[T] {
+	return &observerRegistry[T]{subs: make(map[int]*subscription[T])}
+}
+
+func (r *observerRegistry[T]) subscribe(observer Observer[T]) func() {
+	r.mu.Lock()
+	id := r.nextID
+	r.nextID++
+	sub := newSubscription[T](observer)
+	r.subs[id] = sub
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		delete(r.subs, id)
+		r.mu.Unlock()
+		sub.stop()
+	}
+}
+
+func (r *observerRegistry[T]) notify(dispatch func(Observer[T]) error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, sub := range r.subs {
+		sub := sub
+		sub.enqueue(func() {
+			runObserverCallback(sub.observer, dispatch)
+		})
+	}
+}
+
+func runObserverCallback[T any](observer Observer[T], dispatch func(Observer[T]) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			safeOnError(observer, observerPanicError{r})
+		}
+	}()
+	if err := dispatch(observer); err != nil {
+		safeOnError(observer, err)
+	}
+}
+
+// safeOnError calls observer.OnError behind its own recover, so a panic
+// from OnError itself - the same misbehavior this API exists to isolate
+// - cannot escape the worker goroutine and take down the process. A
+// second panic is simply dropped; there is nowhere left to report it.
+func safeOnError[T any](observer Observer[T], err error) {
+	defer func() {
+		recover()
+	}()
+	observer.OnError(context.Background(), model.Journey[T]{}, err)
+}
+
+type observerPanicError struct {
+	recovered any
+}
+
+func (e observerPanicError) Error() string {
+	return "observer panicked"
+}
+
+// Subscribe registers observer and returns a function that stops its
+// worker pool and removes it from future notifications. It is safe to
+// call Subscribe and the returned unsubscribe func concurrently with
+// Execute.
+func (fs fsmService[T]) Subscribe(observer Observer[T]) (unsubscribe func()) {
+	return fs.observers.subscribe(observer)
+}
+
+func (fs fsmService[T]) notifyStateEntered(ctx context.Context, journey model.Journey[T], fromState, event, toState string) {
+	fs.observers.notify(func(o Observer[T]) error {
+		return o.OnStateEntered(ctx, journey, fromState, event, toState)
+	})
+}
+
+func (fs fsmService[T]) notifyStateExited(ctx context.Context, journey model.Journey[T], fromState, event, toState string) {
+	fs.observers.notify(func(o Observer[T]) error {
+		return o.OnStateExited(ctx, journey, fromState, event, toState)
+	})
+}
+
+func (fs fsmService[T]) notifyJourneyCreated(ctx context.Context, journey model.Journey[T]) {
+	fs.observers.notify(func(o Observer[T]) error {
+		return o.OnJourneyCreated(ctx, journey)
+	})
+}
+
+func (fs fsmService[T]) notifyJourneyResumed(ctx context.Context, journey model.Journey[T]) {
+	fs.observers.notify(func(o Observer[T]) error {
+		return o.OnJourneyResumed(ctx, journey)
+	})
+}
+
+func (fs fsmService[T]) notifyJourneyCompleted(ctx context.Context, journey model.Journey[T]) {
+	fs.observers.notify(func(o Observer[T]) error {
+		return o.OnJourneyCompleted(ctx, journey)
+	})
+}
+
+func (fs fsmService[T]) notifyError(ctx context.Context, journey model.Journey[T], err error) {
+	fs.observers.notify(func(o Observer[T]) error {
+		o.OnError(ctx, journey, err)
+		return nil
+	})
+}
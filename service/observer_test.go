@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thevibegod/fsm/model"
+)
+
+// TestSubscribeDeliversNotificationsAsync checks that a notification
+// queued via notify() reaches the observer without the caller blocking
+// on it, and that unsubscribing stops further delivery.
+func TestSubscribeDeliversNotificationsAsync(t *testing.T) {
+	registry := newObserverRegistry[testData]()
+
+	var mu sync.Mutex
+	var entered []string
+	observer := &funcObserver{
+		onStateEntered: func(ctx context.Context, journey model.Journey[testData], fromState, event, toState string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			entered = append(entered, toState)
+			return nil
+		},
+	}
+
+	unsubscribe := registry.subscribe(observer)
+	registry.notify(func(o Observer[testData]) error {
+		return o.OnStateEntered(context.Background(), model.Journey[testData]{}, "", "go", "start")
+	})
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(entered) == 1
+	})
+
+	unsubscribe()
+	registry.notify(func(o Observer[testData]) error {
+		return o.OnStateEntered(context.Background(), model.Journey[testData]{}, "start", "go", "final")
+	})
+
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(entered) != 1 {
+		t.Fatalf("expected no notifications after unsubscribe, got %v", entered)
+	}
+}
+
+// TestSubscriptionDropsWhenQueueFull checks that enqueue drops tasks
+// once a subscriber's queue is full, rather than growing it unbounded -
+// notify() must never let one slow Observer stall Execute.
+func TestSubscriptionDropsWhenQueueFull(t *testing.T) {
+	sub := newSubscription[testData](&funcObserver{})
+	defer sub.stop()
+
+	block := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(observerPoolSize)
+
+	// Occupy every worker so nothing drains the queue while it fills.
+	for i := 0; i < observerPoolSize; i++ {
+		sub.enqueue(func() {
+			started.Done()
+			<-block
+		})
+	}
+	started.Wait()
+
+	var executed int32
+	total := observerQueueSize + 50
+	for i := 0; i < total; i++ {
+		sub.enqueue(func() {
+			atomic.AddInt32(&executed, 1)
+		})
+	}
+
+	close(block)
+
+	waitFor(t, func() bool {
+		return atomic.LoadInt32(&executed) == observerQueueSize
+	})
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadInt32(&executed); got != observerQueueSize {
+		t.Fatalf("expected exactly %d of %d overflowing tasks to run, got %d", observerQueueSize, total, got)
+	}
+}
+
+// TestRunObserverCallbackRecoversDispatchPanic checks that a panic from
+// the dispatched callback itself is recovered and routed to OnError
+// rather than crashing the worker.
+func TestRunObserverCallbackRecoversDispatchPanic(t *testing.T) {
+	var mu sync.Mutex
+	var gotErr error
+	observer := &funcObserver{
+		onError: func(ctx context.Context, journey model.Journey[testData], err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			gotErr = err
+		},
+	}
+
+	runObserverCallback[testData](observer, func(o Observer[testData]) error {
+		panic("boom")
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotErr == nil {
+		t.Fatal("expected the dispatch panic to be routed to OnError")
+	}
+}
+
+// TestRunObserverCallbackSurvivesOnErrorPanic checks that a panic from
+// OnError itself - the handler this API relies on to report the first
+// failure - is also recovered, instead of escaping the worker goroutine
+// and crashing the process.
+func TestRunObserverCallbackSurvivesOnErrorPanic(t *testing.T) {
+	observer := &funcObserver{
+		onError: func(ctx context.Context, journey model.Journey[testData], err error) {
+			panic("onError also panics")
+		},
+	}
+
+	didNotCrash := make(chan struct{})
+	go func() {
+		runObserverCallback[testData](observer, func(o Observer[testData]) error {
+			panic("boom")
+		})
+		close(didNotCrash)
+	}()
+
+	select {
+	case <-didNotCrash:
+	case <-time.After(time.Second):
+		t.Fatal("runObserverCallback did not return; a panic in OnError must not escape")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("condition not met before deadline")
+}
+
+// funcObserver adapts closures to Observer[testData] so tests only need
+// to provide the callbacks they care about.
+type funcObserver struct {
+	onStateEntered func(ctx context.Context, journey model.Journey[testData], fromState, event, toState string) error
+	onError        func(ctx context.Context, journey model.Journey[testData], err error)
+}
+
+func (o *funcObserver) OnStateEntered(ctx context.Context, journey model.Journey[testData], fromState, event, toState string) error {
+	if o.onStateEntered != nil {
+		return o.onStateEntered(ctx, journey, fromState, event, toState)
+	}
+	return nil
+}
+
+func (o *funcObserver) OnStateExited(ctx context.Context, journey model.Journey[testData], fromState, event, toState string) error {
+	return nil
+}
+
+func (o *funcObserver) OnJourneyCreated(ctx context.Context, journey model.Journey[testData]) error {
+	return nil
+}
+
+func (o *funcObserver) OnJourneyResumed(ctx context.Context, journey model.Journey[testData]) error {
+	return nil
+}
+
+func (o *funcObserver) OnJourneyCompleted(ctx context.Context, journey model.Journey[testData]) error {
+	return nil
+}
+
+func (o *funcObserver) OnError(ctx context.Context, journey model.Journey[testData], err error) {
+	if o.onError != nil {
+		o.onError(ctx, journey, err)
+	}
+}
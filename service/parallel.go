@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/thevibegod/fsm/constants"
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	"github.com/thevibegod/fsm/model"
+)
+
+// Cloner deep-copies journey data so concurrent branches don't race on
+// state reachable through T (slices, maps, pointers). It is only
+// required when a machine declares NextAvailableEvent.ParallelBranches.
+type Cloner[T any] func(data T) T
+
+// Merger folds every branch's final journey data back into one value
+// for the join state. parent is the data as it stood right before the
+// fan-out; branches is keyed by ParallelBranch.Name.
+type Merger[T any] func(parent T, branches map[string]T) (T, *fsmErrors.FsmError)
+
+// branchJID namespaces a fan-out branch's journey under its parent's so
+// it can be saved and inspected (e.g. via History) like any other
+// journey.
+func branchJID(parentJID, branchName string) string {
+	return parentJID + "/" + branchName
+}
+
+// branchEntryStateNames collects the InitialStateName of every
+// ParallelBranch declared on any registered state's NextAvailableEvents.
+func branchEntryStateNames[T any](states map[string]model.FsmState[T]) []string {
+	var names []string
+	for _, state := range states {
+		for _, next := range state.NextAvailableEvents {
+			for _, branch := range next.ParallelBranches {
+				names = append(names, branch.InitialStateName)
+			}
+		}
+	}
+	return names
+}
+
+// branchOnlyStates returns every state reached by walking forward from a
+// ParallelBranch.InitialStateName through ordinary NextAvailableEvents -
+// i.e. states that only exist to be visited inside a fan-out branch.
+// Such a state's StateHandler.Visit ends its branch the same way any
+// other Visit ends a journey, by returning
+// constants.EventNameTransitionComplete, so it may legitimately declare
+// zero NextAvailableEvents without being the machine's one real final
+// state.
+func branchOnlyStates[T any](states map[string]model.FsmState[T], entryNames []string) map[string]bool {
+	result := make(map[string]bool, len(entryNames))
+	queue := make([]string, 0, len(entryNames))
+	for _, name := range entryNames {
+		if !result[name] {
+			result[name] = true
+			queue = append(queue, name)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		state, ok := states[name]
+		if !ok {
+			continue
+		}
+		for _, next := range state.NextAvailableEvents {
+			if !result[next.DestinationStateName] {
+				result[next.DestinationStateName] = true
+				queue = append(queue, next.DestinationStateName)
+			}
+		}
+	}
+	return result
+}
+
+type branchResult[T any] struct {
+	name    string
+	journey model.Journey[T]
+	err     *fsmErrors.FsmError
+}
+
+// resumeFanOut re-drives a fan-out that was still in flight when
+// its journey was last saved: it matches journey.PendingBranches back to
+// the NextAvailableEvent that produced them, then runs handleFanOut
+// again. Branches that already finished and saved a child journey
+// before the crash are reused as-is by runBranch rather than re-run.
+func (fs fsmService[T]) resumeFanOut(ctx context.Context, journey model.Journey[T]) (model.FsmResponse, *fsmErrors.FsmError) {
+	currentState, err := fs.getState(journey.CurrentStage)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+	nextAvailableEvent, err := fs.matchPendingBranches(currentState, journey.JID, journey.PendingBranches)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+	joinState, err := fs.getState(nextAvailableEvent.DestinationStateName)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+
+	journey, data, nextEvent, err := fs.handleFanOut(ctx, currentState, joinState, nextAvailableEvent.ParallelBranches, journey, journey.Data, nextAvailableEvent.Event)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+	journey, lastExecutedState, data, err := fs.runUntilComplete(ctx, journey, data, nextEvent)
+	if err != nil {
+		return model.FsmResponse{}, err
+	}
+	if saveErr := fs.journeyStore.Save(ctx, journey); saveErr != nil {
+		return model.FsmResponse{}, saveErr
+	}
+	fs.notifyJourneyResumed(ctx, journey)
+	if journey.CurrentStage == fs.finalStateName {
+		fs.notifyJourneyCompleted(ctx, journey)
+	}
+	return fs.loadFsmResponse(journey, lastExecutedState, data), nil
+}
+
+// matchPendingBranches finds the NextAvailableEvent whose
+// ParallelBranches produced journey.PendingBranches, by reconstructing
+// the child JIDs each candidate event's branches would have produced and
+// comparing them against what the journey has saved.
+func (fs fsmService[T]) matchPendingBranches(state model.FsmState[T], journeyJID string, pendingBranches []string) (model.NextAvailableEvent, *fsmErrors.FsmError) {
+	pending := make(map[string]bool, len(pendingBranches))
+	for _, jid := range pendingBranches {
+		pending[jid] = true
+	}
+
+	for _, next := range state.NextAvailableEvents {
+		if len(next.ParallelBranches) != len(pending) {
+			continue
+		}
+		match := true
+		for _, branch := range next.ParallelBranches {
+			if !pending[branchJID(journeyJID, branch.Name)] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return next, nil
+		}
+	}
+	return model.NextAvailableEvent{}, fsmErrors.InternalSystemError("cannot find fan-out event matching pending branches for journey " + journeyJID)
+}
+
+// handleFanOut runs branches concurrently against independent clones of
+// journey.Data, merges their results with fs.merger, and then visits
+// joinState with the merged data exactly as handleStateVisit would for
+// a plain transition.
+func (fs fsmService[T]) handleFanOut(ctx context.Context, currentState, joinState model.FsmState[T], branches []model.ParallelBranch, journey model.Journey[T], data any, triggerEvent string) (model.Journey[T], any, string, *fsmErrors.FsmError) {
+	if fs.cloner == nil || fs.merger == nil {
+		return model.Journey[T]{}, nil, "", fsmErrors.InternalSystemError("fan-out event " + triggerEvent + " requires a Cloner and Merger to be configured")
+	}
+
+	childJIDs := make([]string, len(branches))
+	for i, branch := range branches {
+		childJIDs[i] = branchJID(journey.JID, branch.Name)
+	}
+	journey.PendingBranches = childJIDs
+	if saveErr := fs.journeyStore.Save(ctx, journey); saveErr != nil {
+		return model.Journey[T]{}, nil, "", saveErr
+	}
+
+	results := fs.runBranches(ctx, journey, branches)
+
+	branchData := make(map[string]T, len(results))
+	for _, result := range results {
+		if result.err != nil {
+			return model.Journey[T]{}, nil, "", result.err
+		}
+		branchData[result.name] = result.journey.Data
+	}
+
+	mergedData, mergeErr := fs.merger(journey.Data, branchData)
+	if mergeErr != nil {
+		return model.Journey[T]{}, nil, "", mergeErr
+	}
+	journey.Data = mergedData
+	journey.PendingBranches = nil
+
+	return fs.handleStateVisit(ctx, currentState, joinState, journey, data, triggerEvent)
+}
+
+// runBranches fans branches out onto goroutines and waits for all of
+// them to finish. A panic in one branch is reported as that branch's
+// error rather than taking down the other branches (and the rest of the
+// service) with it.
+func (fs fsmService[T]) runBranches(ctx context.Context, parent model.Journey[T], branches []model.ParallelBranch) []branchResult[T] {
+	results := make([]branchResult[T], len(branches))
+	var wg sync.WaitGroup
+	for i, branch := range branches {
+		wg.Add(1)
+		go func(i int, branch model.ParallelBranch) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					results[i] = branchResult[T]{name: branch.Name, err: fsmErrors.InternalSystemError(fmt.Sprintf("branch %s panicked: %v", branch.Name, r))}
+				}
+			}()
+			journey, err := fs.runBranch(ctx, parent, branch)
+			results[i] = branchResult[T]{name: branch.Name, journey: journey, err: err}
+		}(i, branch)
+	}
+	wg.Wait()
+	return results
+}
+
+// runBranch drives one fan-out branch to completion, starting at
+// branch.InitialStateName with a cloned copy of the parent's journey
+// data, and saves it under its own branch JID once it joins. If the
+// branch already has a saved journey - i.e. it finished on a previous
+// attempt at this fan-out, before a crash interrupted a sibling branch -
+// that result is reused instead of re-running the branch, which is what
+// lets resumeFanOut make forward progress on a partially-completed
+// fan-out instead of redoing every branch from scratch.
+func (fs fsmService[T]) runBranch(ctx context.Context, parent model.Journey[T], branch model.ParallelBranch) (model.Journey[T], *fsmErrors.FsmError) {
+	childJID := branchJID(parent.JID, branch.Name)
+	existing, getErr := fs.journeyStore.Get(ctx, childJID)
+	if getErr == nil {
+		return existing, nil
+	}
+	if !fsmErrors.IsNotFound(getErr) {
+		// A transient store failure (timeout, connection drop) is not the
+		// same as the branch never having run: treating it as "not found"
+		// would re-run the branch and, for a non-idempotent StateHandler,
+		// double its side effects.
+		return model.Journey[T]{}, getErr
+	}
+
+	initState, err := fs.getState(branch.InitialStateName)
+	if err != nil {
+		return model.Journey[T]{}, err
+	}
+
+	branchJourney := model.Journey[T]{
+		JID:  childJID,
+		Data: fs.cloner(parent.Data),
+	}
+
+	branchJourney, resp, nextEvent, err := fs.handleStateVisit(ctx, model.FsmState[T]{}, initState, branchJourney, branchJourney.Data, constants.EventNameStart)
+	if err != nil {
+		return model.Journey[T]{}, err
+	}
+
+	branchJourney, _, _, err = fs.runUntilComplete(ctx, branchJourney, resp, nextEvent)
+	if err != nil {
+		return model.Journey[T]{}, err
+	}
+
+	if saveErr := fs.journeyStore.Save(ctx, branchJourney); saveErr != nil {
+		return model.Journey[T]{}, saveErr
+	}
+	return branchJourney, nil
+}
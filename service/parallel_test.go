@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thevibegod/fsm/constants"
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	journeystore "github.com/thevibegod/fsm/journey_store"
+	"github.com/thevibegod/fsm/model"
+)
+
+func cloneTestData(d testData) testData {
+	return testData{Log: append([]string{}, d.Log...)}
+}
+
+// TestNewFsmServiceAllowsBranchOnlyLeafAlongsideFinalState reproduces a
+// machine with one real final state and one single-state fan-out
+// branch: the branch's leaf state has zero NextAvailableEvents by
+// construction (it ends, like any StateHandler.Visit, by returning
+// constants.EventNameTransitionComplete), and must not be mistaken for
+// a second final state.
+func TestNewFsmServiceAllowsBranchOnlyLeafAlongsideFinalState(t *testing.T) {
+	initial := model.FsmState[testData]{
+		Name:         "start",
+		StateHandler: stepHandler("start", "go"),
+		NextAvailableEvents: []model.NextAvailableEvent{
+			{
+				Event:                "go",
+				DestinationStateName: "join",
+				ParallelBranches: []model.ParallelBranch{
+					{Name: "branchA", InitialStateName: "branchLeaf"},
+				},
+			},
+		},
+	}
+	branchLeaf := model.FsmState[testData]{Name: "branchLeaf", StateHandler: stepHandler("branchLeaf", constants.EventNameTransitionComplete)}
+	join := model.FsmState[testData]{
+		Name:                "join",
+		StateHandler:        stepHandler("join", "finish"),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "finish", DestinationStateName: "final"}},
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	merger := func(parent testData, branches map[string]testData) (testData, *fsmErrors.FsmError) {
+		return parent, nil
+	}
+
+	_, err := NewFsmService[testData](initial, []model.FsmState[testData]{branchLeaf, join, final}, journeystore.NewMemoryStore[testData](), cloneTestData, merger)
+	if err != nil {
+		t.Fatalf("NewFsmService rejected a machine with one final state and one single-state branch: %v", err)
+	}
+}
+
+// TestFanOutRunsBranchesAndMergesAtJoin drives a two-branch fan-out
+// through Execute end-to-end and checks that both branches ran and that
+// the configured Merger folded their results back into the journey
+// before the join state was visited.
+func TestFanOutRunsBranchesAndMergesAtJoin(t *testing.T) {
+	initial := model.FsmState[testData]{
+		Name:         "start",
+		StateHandler: stepHandler("start", "go"),
+		NextAvailableEvents: []model.NextAvailableEvent{
+			{
+				Event:                "go",
+				DestinationStateName: "join",
+				ParallelBranches: []model.ParallelBranch{
+					{Name: "a", InitialStateName: "branchA"},
+					{Name: "b", InitialStateName: "branchB"},
+				},
+			},
+		},
+	}
+	branchA := model.FsmState[testData]{Name: "branchA", StateHandler: stepHandler("a", constants.EventNameTransitionComplete)}
+	branchB := model.FsmState[testData]{Name: "branchB", StateHandler: stepHandler("b", constants.EventNameTransitionComplete)}
+	join := model.FsmState[testData]{
+		Name:                "join",
+		StateHandler:        stepHandler("join", "finish"),
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "finish", DestinationStateName: "final"}},
+	}
+	final := model.FsmState[testData]{Name: "final", StateHandler: stepHandler("final", constants.EventNameTransitionComplete)}
+
+	merger := func(parent testData, branches map[string]testData) (testData, *fsmErrors.FsmError) {
+		merged := cloneTestData(parent)
+		merged.Log = append(merged.Log, branches["a"].Log...)
+		merged.Log = append(merged.Log, branches["b"].Log...)
+		return merged, nil
+	}
+
+	journeyStore := journeystore.NewMemoryStore[testData]()
+	fs, err := NewFsmService[testData](initial, []model.FsmState[testData]{branchA, branchB, join, final}, journeyStore, cloneTestData, merger)
+	if err != nil {
+		t.Fatalf("NewFsmService: %v", err)
+	}
+
+	resp, execErr := fs.Execute(context.Background(), model.FsmRequest{Event: constants.EventNameStart})
+	if execErr != nil {
+		t.Fatalf("Execute: %v", execErr)
+	}
+
+	journey, getErr := journeyStore.Get(context.Background(), resp.JID)
+	if getErr != nil {
+		t.Fatalf("journeyStore.Get: %v", getErr)
+	}
+	if journey.CurrentStage != "final" {
+		t.Fatalf("expected journey to finish in %q, got %q", "final", journey.CurrentStage)
+	}
+	if len(journey.PendingBranches) != 0 {
+		t.Fatalf("expected PendingBranches to be cleared after the join, got %v", journey.PendingBranches)
+	}
+
+	hasLabel := func(label string) bool {
+		for _, entry := range journey.Data.Log {
+			if entry == label {
+				return true
+			}
+		}
+		return false
+	}
+	for _, label := range []string{"start", "a", "b", "join", "final"} {
+		if !hasLabel(label) {
+			t.Fatalf("expected journey.Data.Log %v to contain %q", journey.Data.Log, label)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	fsmErrors "github.com/thevibegod/fsm/errors"
+)
+
+// testData is the journey payload shared by this package's tests.
+type testData struct {
+	Log []string
+}
+
+// funcHandler adapts a plain closure to model.StateHandler so tests can
+// wire up states without a bespoke type per state.
+type funcHandler struct {
+	visit func(ctx context.Context, jid string, journeyData any, data any) (any, any, string, *fsmErrors.FsmError)
+}
+
+func (h funcHandler) Visit(ctx context.Context, jid string, journeyData any, data any) (any, any, string, *fsmErrors.FsmError) {
+	return h.visit(ctx, jid, journeyData, data)
+}
+
+func (h funcHandler) Revisit(ctx context.Context, jid string, journeyData any) (any, any, *fsmErrors.FsmError) {
+	return nil, journeyData, nil
+}
+
+// stepHandler is a funcHandler that appends label to the journey's Log
+// and moves on to nextEvent, the most common shape needed in these
+// tests.
+func stepHandler(label, nextEvent string) funcHandler {
+	return funcHandler{visit: func(ctx context.Context, jid string, journeyData any, data any) (any, any, string, *fsmErrors.FsmError) {
+		jd := journeyData.(testData)
+		jd.Log = append(jd.Log, label)
+		return data, jd, nextEvent, nil
+	}}
+}
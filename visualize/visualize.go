@@ -0,0 +1,128 @@
+// Package visualize renders the static state graph of a registered
+// service.FsmService as Graphviz DOT or Mermaid flowchart source, so a
+// wired-up machine can be inspected without running it.
+package visualize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thevibegod/fsm/constants"
+	"github.com/thevibegod/fsm/model"
+	"github.com/thevibegod/fsm/service"
+)
+
+// ToGraphviz renders fs as a Graphviz DOT digraph. The initial state is
+// drawn as a bold double circle, checkpoint states are shaded, and the
+// final state is drawn as a double circle. Implicit `resume` transitions
+// (back to the journey's last checkpoint) are drawn as dashed edges from
+// a synthetic "(resume)" node.
+func ToGraphviz[T any](fs service.FsmService[T]) (string, error) {
+	names, states, err := orderedStates(fs)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph fsm {\n")
+	b.WriteString("  rankdir=LR;\n")
+
+	for _, name := range names {
+		state := states[name]
+		b.WriteString(fmt.Sprintf("  %q [%s];\n", name, graphvizNodeAttrs(fs, state)))
+	}
+
+	for _, name := range names {
+		state := states[name]
+		for _, next := range state.NextAvailableEvents {
+			b.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", name, next.DestinationStateName, next.Event))
+		}
+	}
+
+	for _, name := range names {
+		if states[name].IsCheckpoint {
+			b.WriteString(fmt.Sprintf("  \"(resume)\" -> %q [label=%q, style=dashed];\n", name, constants.EventNameResume))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// ToMermaid renders fs as a Mermaid flowchart, using the same markers
+// and implicit resume edges as ToGraphviz.
+func ToMermaid[T any](fs service.FsmService[T]) (string, error) {
+	names, states, err := orderedStates(fs)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	for _, name := range names {
+		state := states[name]
+		b.WriteString(fmt.Sprintf("  %s[%q]\n", mermaidID(name), mermaidLabel(fs, state)))
+	}
+
+	for _, name := range names {
+		state := states[name]
+		for _, next := range state.NextAvailableEvents {
+			b.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", mermaidID(name), next.Event, mermaidID(next.DestinationStateName)))
+		}
+	}
+
+	for _, name := range names {
+		if states[name].IsCheckpoint {
+			b.WriteString(fmt.Sprintf("  resume((resume)) -.->|%s| %s\n", constants.EventNameResume, mermaidID(name)))
+		}
+	}
+
+	return b.String(), nil
+}
+
+func orderedStates[T any](fs service.FsmService[T]) ([]string, map[string]model.FsmState[T], error) {
+	if err := fs.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	states := fs.States()
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, states, nil
+}
+
+func graphvizNodeAttrs[T any](fs service.FsmService[T], state model.FsmState[T]) string {
+	switch {
+	case state.Name == fs.InitialStateName():
+		return `shape=doublecircle, style=bold, label=` + fmt.Sprintf("%q", state.Name)
+	case state.Name == fs.FinalStateName():
+		return `shape=doublecircle, label=` + fmt.Sprintf("%q", state.Name)
+	case state.IsCheckpoint:
+		return `shape=box, style=filled, fillcolor=lightgrey, label=` + fmt.Sprintf("%q", state.Name)
+	default:
+		return `shape=box, label=` + fmt.Sprintf("%q", state.Name)
+	}
+}
+
+func mermaidLabel[T any](fs service.FsmService[T], state model.FsmState[T]) string {
+	switch {
+	case state.Name == fs.InitialStateName():
+		return state.Name + " (initial)"
+	case state.Name == fs.FinalStateName():
+		return state.Name + " (final)"
+	case state.IsCheckpoint:
+		return state.Name + " (checkpoint)"
+	default:
+		return state.Name
+	}
+}
+
+func mermaidID(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
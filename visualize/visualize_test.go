@@ -0,0 +1,102 @@
+package visualize
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/thevibegod/fsm/constants"
+	fsmErrors "github.com/thevibegod/fsm/errors"
+	journeystore "github.com/thevibegod/fsm/journey_store"
+	"github.com/thevibegod/fsm/model"
+	"github.com/thevibegod/fsm/service"
+)
+
+type vizData struct{}
+
+type vizHandler struct {
+	nextEvent string
+}
+
+func (h vizHandler) Visit(ctx context.Context, jid string, journeyData, data any) (any, any, string, *fsmErrors.FsmError) {
+	return data, journeyData, h.nextEvent, nil
+}
+
+func (h vizHandler) Revisit(ctx context.Context, jid string, journeyData any) (any, any, *fsmErrors.FsmError) {
+	return nil, journeyData, nil
+}
+
+// newVizTestService builds a small three-state machine - a checkpoint
+// "review" state sitting between the initial and final states - so the
+// initial marker, checkpoint marker, and resume edge are all exercised.
+func newVizTestService(t *testing.T) service.FsmService[vizData] {
+	t.Helper()
+	initial := model.FsmState[vizData]{
+		Name:                "start",
+		StateHandler:        vizHandler{nextEvent: "go"},
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "go", DestinationStateName: "review"}},
+	}
+	review := model.FsmState[vizData]{
+		Name:                "review",
+		StateHandler:        vizHandler{nextEvent: "approve"},
+		NextAvailableEvents: []model.NextAvailableEvent{{Event: "approve", DestinationStateName: "final"}},
+		IsCheckpoint:        true,
+	}
+	final := model.FsmState[vizData]{Name: "final", StateHandler: vizHandler{nextEvent: constants.EventNameTransitionComplete}}
+
+	fs, err := service.NewFsmService[vizData](initial, []model.FsmState[vizData]{review, final}, journeystore.NewMemoryStore[vizData](), nil, nil)
+	if err != nil {
+		t.Fatalf("NewFsmService: %v", err)
+	}
+	return fs
+}
+
+func TestToGraphvizRendersMarkersAndEdges(t *testing.T) {
+	fs := newVizTestService(t)
+
+	dot, err := ToGraphviz[vizData](fs)
+	if err != nil {
+		t.Fatalf("ToGraphviz: %v", err)
+	}
+
+	if !strings.HasPrefix(dot, "digraph fsm {") {
+		t.Fatalf("expected a digraph header, got:\n%s", dot)
+	}
+	for _, want := range []string{
+		`"start" [shape=doublecircle, style=bold, label="start"]`,
+		`"final" [shape=doublecircle, label="final"]`,
+		`"review" [shape=box, style=filled, fillcolor=lightgrey, label="review"]`,
+		`"start" -> "review" [label="go"]`,
+		`"review" -> "final" [label="approve"]`,
+		`"(resume)" -> "review" [label="resume", style=dashed]`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("expected DOT output to contain %q, got:\n%s", want, dot)
+		}
+	}
+}
+
+func TestToMermaidRendersMarkersAndEdges(t *testing.T) {
+	fs := newVizTestService(t)
+
+	flowchart, err := ToMermaid[vizData](fs)
+	if err != nil {
+		t.Fatalf("ToMermaid: %v", err)
+	}
+
+	if !strings.HasPrefix(flowchart, "flowchart LR\n") {
+		t.Fatalf("expected a flowchart header, got:\n%s", flowchart)
+	}
+	for _, want := range []string{
+		`start["start (initial)"]`,
+		`final["final (final)"]`,
+		`review["review (checkpoint)"]`,
+		`start -->|go| review`,
+		`review -->|approve| final`,
+		`resume((resume)) -.->|resume| review`,
+	} {
+		if !strings.Contains(flowchart, want) {
+			t.Fatalf("expected Mermaid output to contain %q, got:\n%s", want, flowchart)
+		}
+	}
+}